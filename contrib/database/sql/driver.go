@@ -0,0 +1,197 @@
+package sql
+
+import (
+	"context"
+	"database/sql"
+	"database/sql/driver"
+	"errors"
+	"regexp"
+	"strings"
+
+	"github.com/DataDog/dd-trace-go/tracer"
+
+	opentracing "github.com/opentracing/opentracing-go"
+)
+
+// tracedDriverSuffix distinguishes the name under which we register the
+// traced wrapper of a driver from the name the caller registered it under.
+const tracedDriverSuffix = ".traced"
+
+// tracedDriverName returns the name under which the traced wrapper of
+// driverName is registered with database/sql.
+func tracedDriverName(driverName string) string {
+	return driverName + tracedDriverSuffix
+}
+
+// driverExists reports whether name has already been registered with
+// database/sql.
+func driverExists(name string) bool {
+	for _, d := range sql.Drivers() {
+		if d == name {
+			return true
+		}
+	}
+	return false
+}
+
+// tracedDriver wraps a driver.Driver so that every connection it opens
+// traces its queries, statements and transactions.
+type tracedDriver struct {
+	driver.Driver
+	driverName string
+	cfg        *config
+}
+
+// Open implements driver.Driver.
+func (d *tracedDriver) Open(name string) (driver.Conn, error) {
+	conn, err := d.Driver.Open(name)
+	if err != nil {
+		return nil, err
+	}
+	return &tracedConn{Conn: conn, driverName: d.driverName, cfg: d.cfg}, nil
+}
+
+// tracedConn wraps a driver.Conn, starting a span for every query,
+// statement execution and transaction it's asked to perform. The span's
+// parent is whatever span (if any) is found in the context.Context passed
+// to the *Context methods, restoring distributed tracing through database
+// calls for callers that already carry a span in context.
+type tracedConn struct {
+	driver.Conn
+	driverName string
+	cfg        *config
+}
+
+// startSpan starts a child of the span found in ctx, if any, using the
+// tracer configured for this connection (resolved lazily, not at Register
+// time, so opentracing.SetGlobalTracer after Register still takes effect).
+func (c *tracedConn) startSpan(ctx context.Context, resource string) opentracing.Span {
+	t := c.cfg.tracer
+	if t == nil {
+		t = opentracing.GlobalTracer()
+	}
+	var opts []opentracing.StartSpanOption
+	if parent := opentracing.SpanFromContext(ctx); parent != nil {
+		opts = append(opts, opentracing.ChildOf(parent.Context()))
+	}
+	span := t.StartSpan(c.driverName+".query", opts...)
+	span.SetTag(tracer.ServiceName, c.cfg.serviceName)
+	span.SetTag(tracer.SpanType, "sql")
+	if c.cfg.traceQuery && resource != "" {
+		span.SetTag(tracer.ResourceName, redactQuery(resource))
+		span.SetTag(sqlQueryTag, redactQuery(resource))
+	}
+	if c.cfg.analyticsRate > 0 {
+		span.SetTag(analyticsRateTag, c.cfg.analyticsRate)
+	}
+	return span
+}
+
+// analyticsRateTag is the metric used to mark a span for App Analytics.
+const analyticsRateTag = "_dd1.sr.eausr"
+
+// sqlQueryTag is the tag holding the redacted SQL statement (see WithTraceQuery).
+const sqlQueryTag = "sql.query"
+
+// QueryContext implements driver.QueryerContext. When the wrapped driver
+// only implements the legacy driver.Queryer, the query is traced and run
+// through that instead of returning driver.ErrSkip, which would otherwise
+// make database/sql bypass this method entirely and call the wrapped
+// conn's promoted, untraced Query method directly.
+func (c *tracedConn) QueryContext(ctx context.Context, query string, args []driver.NamedValue) (driver.Rows, error) {
+	span := c.startSpan(ctx, query)
+	defer span.Finish()
+
+	if queryer, ok := c.Conn.(driver.QueryerContext); ok {
+		rows, err := queryer.QueryContext(ctx, query, args)
+		span.SetTag(tracer.Error, err)
+		return rows, err
+	}
+	queryer, ok := c.Conn.(driver.Queryer)
+	if !ok {
+		// Neither interface is supported; database/sql will report this
+		// itself, so there's nothing to tag on the span.
+		return nil, driver.ErrSkip
+	}
+	values, err := namedValuesToValues(args)
+	if err != nil {
+		span.SetTag(tracer.Error, err)
+		return nil, err
+	}
+	rows, err := queryer.Query(query, values)
+	span.SetTag(tracer.Error, err)
+	return rows, err
+}
+
+// ExecContext implements driver.ExecerContext. When the wrapped driver
+// only implements the legacy driver.Execer, the statement is traced and
+// run through that instead of returning driver.ErrSkip, which would
+// otherwise make database/sql bypass this method entirely and call the
+// wrapped conn's promoted, untraced Exec method directly.
+func (c *tracedConn) ExecContext(ctx context.Context, query string, args []driver.NamedValue) (driver.Result, error) {
+	span := c.startSpan(ctx, query)
+	defer span.Finish()
+
+	if execer, ok := c.Conn.(driver.ExecerContext); ok {
+		res, err := execer.ExecContext(ctx, query, args)
+		span.SetTag(tracer.Error, err)
+		return res, err
+	}
+	execer, ok := c.Conn.(driver.Execer)
+	if !ok {
+		// Neither interface is supported; database/sql will report this
+		// itself, so there's nothing to tag on the span.
+		return nil, driver.ErrSkip
+	}
+	values, err := namedValuesToValues(args)
+	if err != nil {
+		span.SetTag(tracer.Error, err)
+		return nil, err
+	}
+	res, err := execer.Exec(query, values)
+	span.SetTag(tracer.Error, err)
+	return res, err
+}
+
+// namedValuesToValues converts context-era driver.NamedValue arguments
+// into the plain driver.Value slice the legacy driver.Queryer/driver.Execer
+// interfaces expect, rejecting anything that relies on named parameters
+// since those interfaces have no way to express them.
+func namedValuesToValues(args []driver.NamedValue) ([]driver.Value, error) {
+	values := make([]driver.Value, len(args))
+	for i, arg := range args {
+		if arg.Name != "" {
+			return nil, errors.New("sqltrace: driver does not support the use of named parameters")
+		}
+		values[i] = arg.Value
+	}
+	return values, nil
+}
+
+// BeginTx implements driver.ConnBeginTx.
+func (c *tracedConn) BeginTx(ctx context.Context, opts driver.TxOptions) (driver.Tx, error) {
+	span := c.startSpan(ctx, "")
+	defer span.Finish()
+
+	connBeginTx, ok := c.Conn.(driver.ConnBeginTx)
+	if !ok {
+		tx, err := c.Conn.Begin()
+		span.SetTag(tracer.Error, err)
+		return tx, err
+	}
+	tx, err := connBeginTx.BeginTx(ctx, opts)
+	span.SetTag(tracer.Error, err)
+	return tx, err
+}
+
+// queryParamPattern matches string and numeric literals in a SQL
+// statement so they can be redacted before being attached to a span; only
+// the query shape (e.g. "select * from user where id = ?") should ever
+// leave the process, never parameter values.
+var queryParamPattern = regexp.MustCompile(`'[^']*'|\b\d+\b`)
+
+// redactQuery replaces literal values in a SQL statement with "?",
+// mirroring how the statement would look as a prepared query.
+func redactQuery(query string) string {
+	return queryParamPattern.ReplaceAllString(strings.TrimSpace(query), "?")
+}
@@ -0,0 +1,64 @@
+package sql
+
+import (
+	opentracing "github.com/opentracing/opentracing-go"
+)
+
+// config holds the options used to trace a registered driver.
+type config struct {
+	serviceName string
+	tracer      opentracing.Tracer
+
+	// analyticsRate configures the rate at which traced SQL spans are
+	// marked for App Analytics (0 disables it).
+	analyticsRate float64
+
+	// traceQuery controls whether the query string is recorded as the
+	// "sql.query" tag. Redaction (see redactQuery) is always applied.
+	traceQuery bool
+}
+
+// Option customizes the behavior of Register/RegisterWithOptions.
+type Option func(*config)
+
+func defaults(cfg *config) {
+	// cfg.tracer is intentionally left nil here: startSpan falls back to
+	// opentracing.GlobalTracer() at span-start time, not Register time, so
+	// that setting the global tracer after Register still takes effect.
+	// Setting it here would snapshot the global tracer once and for all.
+	cfg.traceQuery = true
+}
+
+// WithServiceName sets the service name to be used for the traced driver.
+func WithServiceName(name string) Option {
+	return func(cfg *config) {
+		cfg.serviceName = name
+	}
+}
+
+// WithTracer sets the OpenTracing tracer to use for spans started by the
+// traced driver. Without this option, the driver resolves
+// opentracing.GlobalTracer() at the time each span starts, so that setting
+// the global tracer after Register still takes effect and multiple
+// tracers can coexist in the same process.
+func WithTracer(t opentracing.Tracer) Option {
+	return func(cfg *config) {
+		cfg.tracer = t
+	}
+}
+
+// WithAnalyticsRate sets the App Analytics sample rate for traced SQL
+// spans. Using this feature is optional and a rate <= 0 disables it.
+func WithAnalyticsRate(rate float64) Option {
+	return func(cfg *config) {
+		cfg.analyticsRate = rate
+	}
+}
+
+// WithTraceQuery controls whether the (redacted) SQL statement is recorded
+// as the "sql.query" tag on each span. It is enabled by default.
+func WithTraceQuery(enabled bool) Option {
+	return func(cfg *config) {
+		cfg.traceQuery = enabled
+	}
+}
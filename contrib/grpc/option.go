@@ -0,0 +1,43 @@
+// Package grpc provides gRPC client interceptors that keep a caller's
+// Datadog trace context alive across RPCs made by processes that never
+// start a local span of their own.
+package grpc
+
+import "github.com/DataDog/dd-trace-go/tracer"
+
+// config holds the options for the passthrough interceptors.
+type config struct {
+	envPropagator     tracer.Propagator
+	textMapPropagator tracer.Propagator
+}
+
+// Option customizes a passthrough interceptor.
+type Option func(*config)
+
+func newConfig(opts []Option) *config {
+	cfg := &config{
+		envPropagator:     tracer.NewEnvPropagator(),
+		textMapPropagator: tracer.NewTextMapPropagator("", "", ""),
+	}
+	for _, opt := range opts {
+		opt(cfg)
+	}
+	return cfg
+}
+
+// WithEnvPropagator sets the Propagator used to read the caller's trace
+// context from the environment. It defaults to tracer.NewEnvPropagator().
+func WithEnvPropagator(p tracer.Propagator) Option {
+	return func(cfg *config) {
+		cfg.envPropagator = p
+	}
+}
+
+// WithTextMapPropagator sets the Propagator used to write the
+// synthesized passthrough context into outgoing gRPC metadata. It
+// defaults to the standard Datadog TextMapPropagator.
+func WithTextMapPropagator(p tracer.Propagator) Option {
+	return func(cfg *config) {
+		cfg.textMapPropagator = p
+	}
+}
@@ -0,0 +1,77 @@
+package grpc
+
+import (
+	"context"
+
+	opentracing "github.com/opentracing/opentracing-go"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/metadata"
+)
+
+// metadataCarrier adapts grpc metadata.MD to the opentracing.TextMapWriter
+// interface so a tracer.Propagator can inject trace context into outgoing
+// gRPC metadata.
+type metadataCarrier metadata.MD
+
+// Set implements opentracing.TextMapWriter.
+func (c metadataCarrier) Set(key, val string) {
+	metadata.MD(c).Append(key, val)
+}
+
+// UnaryClientInterceptor returns a grpc.UnaryClientInterceptor that keeps
+// a caller's trace unbroken across this RPC even when the calling process
+// has no active span of its own. This is the case for short-lived helper
+// processes spawned mid-request (git hooks, sidecars, CLI tools invoked by
+// a server): they typically receive trace context as environment
+// variables, and if they do nothing with it the RPCs they make back to
+// the parent service show up as disconnected traces.
+//
+// When no span is found in ctx, the interceptor extracts a SpanContext
+// from the environment (see tracer.EnvPropagator) and injects it into the
+// outgoing request metadata unchanged, without starting a local span. If a
+// span is already active, the interceptor does nothing and lets normal
+// tracing handle propagation.
+func UnaryClientInterceptor(opts ...Option) grpc.UnaryClientInterceptor {
+	cfg := newConfig(opts)
+	return func(ctx context.Context, method string, req, reply interface{}, cc *grpc.ClientConn, invoker grpc.UnaryInvoker, callOpts ...grpc.CallOption) error {
+		ctx = injectPassthrough(ctx, cfg)
+		return invoker(ctx, method, req, reply, cc, callOpts...)
+	}
+}
+
+// StreamClientInterceptor is the streaming equivalent of
+// UnaryClientInterceptor.
+func StreamClientInterceptor(opts ...Option) grpc.StreamClientInterceptor {
+	cfg := newConfig(opts)
+	return func(ctx context.Context, desc *grpc.StreamDesc, cc *grpc.ClientConn, method string, streamer grpc.Streamer, callOpts ...grpc.CallOption) (grpc.ClientStream, error) {
+		ctx = injectPassthrough(ctx, cfg)
+		return streamer(ctx, desc, cc, method, callOpts...)
+	}
+}
+
+// injectPassthrough synthesizes a SpanContext from the environment and
+// injects it into ctx's outgoing gRPC metadata, unless a local span is
+// already active (in which case regular tracing instrumentation is
+// expected to handle propagation instead).
+func injectPassthrough(ctx context.Context, cfg *config) context.Context {
+	if opentracing.SpanFromContext(ctx) != nil {
+		return ctx
+	}
+
+	sc, err := cfg.envPropagator.Extract(nil)
+	if err != nil {
+		// No trace context in the environment; nothing to forward.
+		return ctx
+	}
+
+	md, ok := metadata.FromOutgoingContext(ctx)
+	if ok {
+		md = md.Copy()
+	} else {
+		md = metadata.MD{}
+	}
+	if err := cfg.textMapPropagator.Inject(sc, metadataCarrier(md)); err != nil {
+		return ctx
+	}
+	return metadata.NewOutgoingContext(ctx, md)
+}
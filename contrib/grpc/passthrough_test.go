@@ -0,0 +1,74 @@
+package grpc
+
+import (
+	"context"
+	"testing"
+
+	opentracing "github.com/opentracing/opentracing-go"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/metadata"
+)
+
+func TestUnaryClientInterceptorPassthrough(t *testing.T) {
+	t.Setenv("DD_TRACE_ID", "42")
+	t.Setenv("DD_PARENT_ID", "100")
+
+	var gotMD metadata.MD
+	invoker := func(ctx context.Context, method string, req, reply interface{}, cc *grpc.ClientConn, opts ...grpc.CallOption) error {
+		gotMD, _ = metadata.FromOutgoingContext(ctx)
+		return nil
+	}
+
+	interceptor := UnaryClientInterceptor()
+	if err := interceptor(context.Background(), "/service/Method", nil, nil, nil, invoker); err != nil {
+		t.Fatalf("interceptor: %v", err)
+	}
+
+	if got := gotMD.Get("x-datadog-trace-id"); len(got) != 1 || got[0] != "42" {
+		t.Fatalf("x-datadog-trace-id = %v, want [42]", got)
+	}
+	if got := gotMD.Get("x-datadog-parent-id"); len(got) != 1 || got[0] != "100" {
+		t.Fatalf("x-datadog-parent-id = %v, want [100]", got)
+	}
+}
+
+func TestUnaryClientInterceptorNoEnvContext(t *testing.T) {
+	var called bool
+	invoker := func(ctx context.Context, method string, req, reply interface{}, cc *grpc.ClientConn, opts ...grpc.CallOption) error {
+		called = true
+		if _, ok := metadata.FromOutgoingContext(ctx); ok {
+			t.Fatal("expected no outgoing metadata without a trace context in the environment")
+		}
+		return nil
+	}
+
+	interceptor := UnaryClientInterceptor()
+	if err := interceptor(context.Background(), "/service/Method", nil, nil, nil, invoker); err != nil {
+		t.Fatalf("interceptor: %v", err)
+	}
+	if !called {
+		t.Fatal("invoker was not called")
+	}
+}
+
+func TestUnaryClientInterceptorSkipsWithLocalSpan(t *testing.T) {
+	t.Setenv("DD_TRACE_ID", "42")
+	t.Setenv("DD_PARENT_ID", "100")
+
+	md := metadata.MD{"x-existing": []string{"value"}}
+	ctx := metadata.NewOutgoingContext(context.Background(), md)
+	ctx = opentracing.ContextWithSpan(ctx, opentracing.NoopTracer{}.StartSpan("op"))
+
+	invoker := func(ctx context.Context, method string, req, reply interface{}, cc *grpc.ClientConn, opts ...grpc.CallOption) error {
+		gotMD, _ := metadata.FromOutgoingContext(ctx)
+		if len(gotMD.Get("x-datadog-trace-id")) != 0 {
+			t.Fatal("passthrough should not run when a local span is already active")
+		}
+		return nil
+	}
+
+	interceptor := UnaryClientInterceptor()
+	if err := interceptor(ctx, "/service/Method", nil, nil, nil, invoker); err != nil {
+		t.Fatalf("interceptor: %v", err)
+	}
+}
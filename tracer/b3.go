@@ -0,0 +1,118 @@
+package tracer
+
+import (
+	"strconv"
+	"strings"
+
+	opentracing "github.com/opentracing/opentracing-go"
+)
+
+// B3 header names, as defined by https://github.com/openzipkin/b3-propagation.
+const (
+	b3TraceIDHeader      = "X-B3-TraceId"
+	b3SpanIDHeader       = "X-B3-SpanId"
+	b3ParentSpanIDHeader = "X-B3-ParentSpanId"
+	b3SampledHeader      = "X-B3-Sampled"
+	b3FlagsHeader        = "X-B3-Flags"
+)
+
+// B3Propagator implements Propagator using the Zipkin B3 header format,
+// allowing dd-trace-go to interoperate with services instrumented with
+// Zipkin or any other B3-compatible tracer. Select it with
+// WithTextMapPropagator(NewB3Propagator()).
+type B3Propagator struct{}
+
+// NewB3Propagator returns a Propagator that reads and writes the B3 headers.
+func NewB3Propagator() *B3Propagator {
+	return &B3Propagator{}
+}
+
+// Inject implements Propagator.
+func (p *B3Propagator) Inject(context *spanContext, carrier interface{}) error {
+	writer, ok := carrier.(opentracing.TextMapWriter)
+	if !ok {
+		return ErrInvalidCarrier
+	}
+	writer.Set(b3TraceIDHeader, traceIDToHex(context.traceIDHigh, context.traceID))
+	writer.Set(b3SpanIDHeader, strconv.FormatUint(context.spanID, 16))
+	if context.parentID != 0 {
+		writer.Set(b3ParentSpanIDHeader, strconv.FormatUint(context.parentID, 16))
+	}
+	if context.sampled {
+		writer.Set(b3SampledHeader, "1")
+	} else {
+		writer.Set(b3SampledHeader, "0")
+	}
+	return nil
+}
+
+// Extract implements Propagator.
+func (p *B3Propagator) Extract(carrier interface{}) (*spanContext, error) {
+	reader, ok := carrier.(opentracing.TextMapReader)
+	if !ok {
+		return nil, ErrInvalidCarrier
+	}
+
+	var ctx spanContext
+	var gotTraceID, gotSpanID, gotSampled, debugFlag bool
+	var sampled bool
+	err := reader.ForeachKey(func(k, v string) error {
+		switch strings.ToLower(k) {
+		case strings.ToLower(b3TraceIDHeader):
+			high, low, err := hexToTraceID(v)
+			if err != nil {
+				return err
+			}
+			ctx.traceIDHigh = high
+			ctx.traceID = low
+			gotTraceID = true
+		case strings.ToLower(b3SpanIDHeader):
+			id, err := strconv.ParseUint(v, 16, 64)
+			if err != nil {
+				return err
+			}
+			ctx.spanID = id
+			gotSpanID = true
+		case strings.ToLower(b3ParentSpanIDHeader):
+			id, err := strconv.ParseUint(v, 16, 64)
+			if err != nil {
+				return err
+			}
+			ctx.parentID = id
+		case strings.ToLower(b3SampledHeader):
+			sampled = v == "1" || v == "true"
+			gotSampled = true
+		case strings.ToLower(b3FlagsHeader):
+			if v == "1" {
+				debugFlag = true
+			}
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	if !gotTraceID || !gotSpanID {
+		return nil, ErrSpanContextNotFound
+	}
+
+	// ForeachKey iterates the carrier in whatever order it chooses (e.g. Go
+	// map order), so the sampled/flags precedence can't be resolved inline
+	// per-key without the outcome depending on that order. Apply it once,
+	// after every header has been seen: the debug flag always wins.
+	switch {
+	case debugFlag:
+		// Per the B3 spec, X-B3-Flags=1 forces sampling on regardless of
+		// X-B3-Sampled.
+		ctx.sampled = true
+	case gotSampled:
+		ctx.sampled = sampled
+	default:
+		// Neither X-B3-Sampled nor X-B3-Flags was present: per the B3 spec
+		// this means the upstream service deferred the sampling decision to
+		// us, not that it was rejected. Default to sampled so the trace
+		// isn't silently dropped.
+		ctx.sampled = true
+	}
+	return &ctx, nil
+}
@@ -0,0 +1,169 @@
+package tracer
+
+import (
+	"encoding/json"
+	"net/http"
+	"sync"
+	"time"
+)
+
+const (
+	// defaultMaxBaggageValueLength is the value length cap applied by
+	// DefaultBaggageRestrictionManager.
+	defaultMaxBaggageValueLength = 2048
+
+	// baggageRestrictionsUpdateMetric counts baggage items dropped or
+	// truncated because of a BaggageRestrictionManager decision.
+	baggageRestrictionsUpdateMetric = "trace.baggage.restrictions.update"
+
+	// baggageTruncatedTag is set on a span, with the offending key as its
+	// value, whenever a baggage value is truncated to fit within the
+	// restriction's maxValueLength.
+	baggageTruncatedTag = "baggage.truncated"
+
+	// defaultBaggageRestrictionPollPeriod is how often a
+	// RemoteBaggageRestrictionManager refreshes its allow-list from the
+	// agent.
+	defaultBaggageRestrictionPollPeriod = 60 * time.Second
+)
+
+// BaggageRestrictionManager decides whether a baggage key may be set on a
+// Span and, if so, how long its value is allowed to be. It guards against
+// the unbounded key/value pairs SetBaggageItem would otherwise accept,
+// which is a common footgun when baggage crosses service boundaries.
+type BaggageRestrictionManager interface {
+	// IsValidBaggageKey reports whether key may be set as baggage and, if
+	// allowed, the maximum length its value may have (<=0 means no limit).
+	IsValidBaggageKey(key string) (allowed bool, maxValueLength int)
+}
+
+// defaultBaggageRestrictionManager is used whenever a Tracer has no
+// BaggageRestrictionManager configured.
+var defaultBaggageRestrictionManager BaggageRestrictionManager = NewDefaultBaggageRestrictionManager(0)
+
+// DefaultBaggageRestrictionManager allows any baggage key, capping every
+// value at MaxValueLength.
+type DefaultBaggageRestrictionManager struct {
+	// MaxValueLength is the maximum number of bytes a baggage value may
+	// have before it gets truncated.
+	MaxValueLength int
+}
+
+// NewDefaultBaggageRestrictionManager returns a DefaultBaggageRestrictionManager
+// with the given value length cap. A maxValueLength <= 0 falls back to
+// defaultMaxBaggageValueLength.
+func NewDefaultBaggageRestrictionManager(maxValueLength int) *DefaultBaggageRestrictionManager {
+	if maxValueLength <= 0 {
+		maxValueLength = defaultMaxBaggageValueLength
+	}
+	return &DefaultBaggageRestrictionManager{MaxValueLength: maxValueLength}
+}
+
+// IsValidBaggageKey implements BaggageRestrictionManager.
+func (m *DefaultBaggageRestrictionManager) IsValidBaggageKey(key string) (bool, int) {
+	return true, m.MaxValueLength
+}
+
+// baggageRestriction is the JSON shape returned by the agent's baggage
+// restrictions endpoint.
+type baggageRestriction struct {
+	Key            string `json:"key"`
+	MaxValueLength int    `json:"max_value_length"`
+}
+
+// RemoteBaggageRestrictionManager polls the Datadog agent for a
+// per-service allow-list of baggage keys, refusing any key the agent
+// hasn't whitelisted.
+type RemoteBaggageRestrictionManager struct {
+	agentAddr   string
+	serviceName string
+	pollPeriod  time.Duration
+	client      *http.Client
+
+	mu           sync.RWMutex
+	restrictions map[string]int // key -> maxValueLength
+
+	stop chan struct{}
+	wg   sync.WaitGroup
+}
+
+// NewRemoteBaggageRestrictionManager returns a RemoteBaggageRestrictionManager
+// that polls agentAddr every pollPeriod for the baggage key allow-list of
+// serviceName. A pollPeriod <= 0 falls back to
+// defaultBaggageRestrictionPollPeriod. The returned manager has no allowed
+// keys until its first successful poll, matching a fail-closed default.
+func NewRemoteBaggageRestrictionManager(agentAddr, serviceName string, pollPeriod time.Duration) *RemoteBaggageRestrictionManager {
+	if pollPeriod <= 0 {
+		pollPeriod = defaultBaggageRestrictionPollPeriod
+	}
+	m := &RemoteBaggageRestrictionManager{
+		agentAddr:    agentAddr,
+		serviceName:  serviceName,
+		pollPeriod:   pollPeriod,
+		client:       &http.Client{Timeout: 5 * time.Second},
+		restrictions: make(map[string]int),
+		stop:         make(chan struct{}),
+	}
+	m.poll()
+	m.wg.Add(1)
+	go m.pollLoop()
+	return m
+}
+
+// IsValidBaggageKey implements BaggageRestrictionManager.
+func (m *RemoteBaggageRestrictionManager) IsValidBaggageKey(key string) (bool, int) {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+	maxLen, allowed := m.restrictions[key]
+	return allowed, maxLen
+}
+
+// Close stops the background polling goroutine.
+func (m *RemoteBaggageRestrictionManager) Close() {
+	close(m.stop)
+	m.wg.Wait()
+}
+
+func (m *RemoteBaggageRestrictionManager) pollLoop() {
+	defer m.wg.Done()
+	ticker := time.NewTicker(m.pollPeriod)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-ticker.C:
+			m.poll()
+		case <-m.stop:
+			return
+		}
+	}
+}
+
+func (m *RemoteBaggageRestrictionManager) poll() {
+	url := "http://" + m.agentAddr + "/v0.3/baggage-restrictions?service=" + m.serviceName
+	resp, err := m.client.Get(url)
+	if err != nil {
+		return
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		// Keep the last-good restrictions (e.g. an older agent without this
+		// endpoint) rather than treating the response as "zero restrictions
+		// allowed", which combined with the fail-closed default would
+		// silently drop all baggage.
+		return
+	}
+
+	var restrictions []baggageRestriction
+	if err := json.NewDecoder(resp.Body).Decode(&restrictions); err != nil {
+		return
+	}
+	updated := make(map[string]int, len(restrictions))
+	for _, r := range restrictions {
+		updated[r.Key] = r.MaxValueLength
+	}
+
+	m.mu.Lock()
+	m.restrictions = updated
+	m.mu.Unlock()
+}
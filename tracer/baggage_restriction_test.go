@@ -0,0 +1,89 @@
+package tracer
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+func TestDefaultBaggageRestrictionManager(t *testing.T) {
+	m := NewDefaultBaggageRestrictionManager(10)
+
+	allowed, max := m.IsValidBaggageKey("anything")
+	if !allowed {
+		t.Fatal("DefaultBaggageRestrictionManager should allow all keys")
+	}
+	if max != 10 {
+		t.Fatalf("got max %d, want 10", max)
+	}
+}
+
+func TestDefaultBaggageRestrictionManagerFallbackLength(t *testing.T) {
+	m := NewDefaultBaggageRestrictionManager(0)
+
+	if _, max := m.IsValidBaggageKey("k"); max != defaultMaxBaggageValueLength {
+		t.Fatalf("got max %d, want %d", max, defaultMaxBaggageValueLength)
+	}
+}
+
+type fakeBaggageRestrictionManager struct {
+	allowedKeys map[string]int
+}
+
+func (f *fakeBaggageRestrictionManager) IsValidBaggageKey(key string) (bool, int) {
+	max, ok := f.allowedKeys[key]
+	return ok, max
+}
+
+func TestSpanSetBaggageItemRejectsDisallowedKey(t *testing.T) {
+	tracer := New(WithBaggageRestrictionManager(&fakeBaggageRestrictionManager{
+		allowedKeys: map[string]int{"allowed": 0},
+	}))
+	root := tracer.StartSpan("web.request")
+	root.SetBaggageItem("denied", "x")
+
+	if v := root.BaggageItem("denied"); v != "" {
+		t.Fatalf("disallowed baggage key was set: %q", v)
+	}
+}
+
+func TestRemoteBaggageRestrictionManagerKeepsLastGoodOnNonOKStatus(t *testing.T) {
+	var hits int32
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if atomic.AddInt32(&hits, 1) == 1 {
+			json.NewEncoder(w).Encode([]baggageRestriction{{Key: "allowed", MaxValueLength: 10}})
+			return
+		}
+		w.WriteHeader(http.StatusNotFound)
+	}))
+	defer srv.Close()
+
+	m := NewRemoteBaggageRestrictionManager(strings.TrimPrefix(srv.URL, "http://"), "svc", time.Hour)
+	defer m.Close()
+
+	if allowed, _ := m.IsValidBaggageKey("allowed"); !allowed {
+		t.Fatal("expected key to be allowed after the first successful poll")
+	}
+
+	m.poll() // a later poll against an agent returning 404
+
+	if allowed, _ := m.IsValidBaggageKey("allowed"); !allowed {
+		t.Fatal("a non-200 poll response should keep the last-good restrictions")
+	}
+}
+
+func TestSpanSetBaggageItemTruncatesLongValue(t *testing.T) {
+	tracer := New(WithBaggageRestrictionManager(&fakeBaggageRestrictionManager{
+		allowedKeys: map[string]int{"item": 4},
+	}))
+	root := tracer.StartSpan("web.request")
+	root.SetBaggageItem("item", "toolong")
+
+	if v := root.BaggageItem("item"); v != "tool" {
+		t.Fatalf("got baggage value %q, want truncated \"tool\"", v)
+	}
+}
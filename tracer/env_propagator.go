@@ -0,0 +1,141 @@
+package tracer
+
+import (
+	"os"
+	"strconv"
+	"strings"
+)
+
+// Environment variable names used by EnvPropagator. Short-lived helper
+// processes spawned mid-request (git hooks, sidecars, CLI tools invoked by
+// a server) typically receive their trace context this way rather than
+// through HTTP headers.
+const (
+	envTraceIDKey          = "DD_TRACE_ID"
+	envParentIDKey         = "DD_PARENT_ID"
+	envSamplingPriorityKey = "DD_SAMPLING_PRIORITY"
+	envBaggagePrefix       = "DD_BAGGAGE_"
+)
+
+// EnvPropagator implements Propagator by reading and writing trace context
+// through process environment variables instead of a carrier, for
+// short-lived helper processes that receive their trace context as
+// environment variables rather than headers. Its carrier argument is
+// ignored; pass nil.
+type EnvPropagator struct {
+	// environ returns the process environment as "KEY=VALUE" strings.
+	// Overridable for testing; nil means os.Environ.
+	environ func() []string
+
+	// setenv sets a single process environment variable. Overridable for
+	// testing; nil means os.Setenv.
+	setenv func(key, value string) error
+}
+
+// NewEnvPropagator returns a Propagator that reads/writes the DD_TRACE_ID,
+// DD_PARENT_ID, DD_SAMPLING_PRIORITY and DD_BAGGAGE_* environment
+// variables of the current process.
+func NewEnvPropagator() *EnvPropagator {
+	return &EnvPropagator{}
+}
+
+// newMapEnvPropagator returns an EnvPropagator backed by an in-memory map
+// rather than the real process environment, so callers (or tests) can
+// inject/extract against a synthetic environment, e.g. the one about to be
+// handed to a child process.
+func newMapEnvPropagator(env map[string]string) *EnvPropagator {
+	return &EnvPropagator{
+		environ: func() []string {
+			kv := make([]string, 0, len(env))
+			for k, v := range env {
+				kv = append(kv, k+"="+v)
+			}
+			return kv
+		},
+		setenv: func(key, value string) error {
+			env[key] = value
+			return nil
+		},
+	}
+}
+
+// Inject implements Propagator. The carrier is ignored: the context is
+// written to the process environment (or the map supplied to
+// newMapEnvPropagator), since that's how it reaches a child process.
+func (p *EnvPropagator) Inject(context *spanContext, carrier interface{}) error {
+	setenv := p.setenv
+	if setenv == nil {
+		setenv = os.Setenv
+	}
+	if err := setenv(envTraceIDKey, strconv.FormatUint(context.traceID, 10)); err != nil {
+		return err
+	}
+	if err := setenv(envParentIDKey, strconv.FormatUint(context.spanID, 10)); err != nil {
+		return err
+	}
+	if context.hasSamplingPriority {
+		if err := setenv(envSamplingPriorityKey, strconv.Itoa(context.samplingPriority)); err != nil {
+			return err
+		}
+	}
+	for k, v := range context.baggage {
+		if err := setenv(envBaggagePrefix+strings.ToUpper(k), v); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// Extract implements Propagator. The carrier is ignored: the context is
+// read from the process environment (or the map supplied to
+// newMapEnvPropagator).
+func (p *EnvPropagator) Extract(carrier interface{}) (*spanContext, error) {
+	environ := p.environ
+	if environ == nil {
+		environ = os.Environ
+	}
+
+	var ctx spanContext
+	var gotTraceID, gotSpanID bool
+	for _, kv := range environ() {
+		parts := strings.SplitN(kv, "=", 2)
+		if len(parts) != 2 {
+			continue
+		}
+		key, val := parts[0], parts[1]
+		switch {
+		case key == envTraceIDKey:
+			id, err := strconv.ParseUint(val, 10, 64)
+			if err != nil {
+				return nil, err
+			}
+			ctx.traceID = id
+			gotTraceID = true
+		case key == envParentIDKey:
+			id, err := strconv.ParseUint(val, 10, 64)
+			if err != nil {
+				return nil, err
+			}
+			ctx.spanID = id
+			ctx.parentID = id
+			gotSpanID = true
+		case key == envSamplingPriorityKey:
+			priority, err := strconv.Atoi(val)
+			if err != nil {
+				return nil, err
+			}
+			ctx.hasSamplingPriority = true
+			ctx.samplingPriority = priority
+		case strings.HasPrefix(key, envBaggagePrefix):
+			if ctx.baggage == nil {
+				ctx.baggage = make(map[string]string)
+			}
+			ctx.baggage[strings.ToLower(key[len(envBaggagePrefix):])] = val
+		}
+	}
+	if !gotTraceID || !gotSpanID {
+		return nil, ErrSpanContextNotFound
+	}
+	ctx.sampled = true
+	return &ctx, nil
+}
@@ -0,0 +1,38 @@
+package tracer
+
+import "testing"
+
+func TestEnvPropagatorRoundTrip(t *testing.T) {
+	env := map[string]string{}
+	p := newMapEnvPropagator(env)
+
+	ctx := &spanContext{
+		traceID: 42,
+		spanID:  100,
+		baggage: map[string]string{"user": "alice"},
+	}
+	if err := p.Inject(ctx, nil); err != nil {
+		t.Fatalf("Inject: %v", err)
+	}
+	if env["DD_TRACE_ID"] != "42" || env["DD_PARENT_ID"] != "100" {
+		t.Fatalf("unexpected env: %+v", env)
+	}
+
+	extracted, err := p.Extract(nil)
+	if err != nil {
+		t.Fatalf("Extract: %v", err)
+	}
+	if extracted.traceID != ctx.traceID || extracted.spanID != ctx.spanID {
+		t.Fatalf("got %+v, want %+v", extracted, ctx)
+	}
+	if extracted.baggage["user"] != "alice" {
+		t.Fatalf("baggage not round-tripped: %+v", extracted.baggage)
+	}
+}
+
+func TestEnvPropagatorExtractMissingContext(t *testing.T) {
+	p := newMapEnvPropagator(map[string]string{})
+	if _, err := p.Extract(nil); err != ErrSpanContextNotFound {
+		t.Fatalf("got %v, want ErrSpanContextNotFound", err)
+	}
+}
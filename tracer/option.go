@@ -28,6 +28,36 @@ type config struct {
 
 	// binaryPropagator is the Binary propagator used for Context propagation.
 	binaryPropagator Propagator
+
+	// maxLogsPerSpan caps the number of log records (LogFields, LogKV, ...)
+	// kept on a single Span. Beyond the cap, log records are dropped and
+	// counted in the "_dd.logs.dropped" metric. 0 means unlimited.
+	maxLogsPerSpan int
+
+	// generate128BitTraceID, when true, fills the high 64 bits of new root
+	// spans' trace IDs so they interoperate with W3C/B3 services that use
+	// 128-bit trace IDs. It defaults to false to keep the existing 64-bit
+	// wire format.
+	generate128BitTraceID bool
+
+	// traceIDGenerator produces the high bits of a 128-bit trace ID. It is
+	// only consulted when generate128BitTraceID is true; nil means use
+	// defaultTraceIDGenerator.
+	traceIDGenerator traceIDGenerator
+
+	// spanPool, when true, recycles *Span values through a sync.Pool instead
+	// of allocating a fresh one per span. See WithSpanPool.
+	spanPool bool
+
+	// baggageRestrictionManager decides which baggage keys Span.SetBaggageItem
+	// accepts and how long their values may be. nil means
+	// defaultBaggageRestrictionManager.
+	baggageRestrictionManager BaggageRestrictionManager
+
+	// reporter delivers finished, sampled spans to a tracing backend. nil
+	// means an AgentReporter pointed at agentAddr (see resolveReporter),
+	// preserving the historical default of talking to the Datadog agent.
+	reporter Reporter
 }
 
 type Option func(*config)
@@ -63,6 +93,21 @@ func WithAgentAddr(addr string) Option {
 	}
 }
 
+// WithReporter sets the Reporter used to deliver finished spans, in place
+// of the default AgentReporter. See Reporter, LoggingReporter and
+// CompositeReporter for built-in alternatives, e.g. for dual-writing to
+// the agent and stdout during a migration:
+//
+//	tracer.New(tracer.WithReporter(tracer.NewCompositeReporter(
+//		tracer.NewAgentReporter("localhost:8126"),
+//		tracer.NewLoggingReporter(os.Stdout),
+//	)))
+func WithReporter(r Reporter) Option {
+	return func(c *config) {
+		c.reporter = r
+	}
+}
+
 func WithGlobalTag(k string, v interface{}) Option {
 	return func(c *config) {
 		if c.globalTags == nil {
@@ -99,3 +144,60 @@ func WithBinaryPropagator(p Propagator) Option {
 		c.binaryPropagator = p
 	}
 }
+
+// WithEnvPropagator sets the TextMap propagator to EnvPropagator, so the
+// Tracer reads and writes trace context through DD_TRACE_ID, DD_PARENT_ID,
+// DD_SAMPLING_PRIORITY and DD_BAGGAGE_* environment variables rather than
+// HTTP-style headers. This suits short-lived helper processes (git hooks,
+// sidecars, CLI tools) that receive their trace context that way.
+func WithEnvPropagator() Option {
+	return func(c *config) {
+		c.textMapPropagator = NewEnvPropagator()
+	}
+}
+
+// WithMaxLogsPerSpan caps the number of log records a single Span retains
+// from LogFields/LogKV/LogEvent/Log. Once the cap is reached, further log
+// records are dropped and counted instead of stored. A value <= 0 disables
+// the cap.
+func WithMaxLogsPerSpan(n int) Option {
+	return func(c *config) {
+		c.maxLogsPerSpan = n
+	}
+}
+
+// WithGenerate128BitTraceID enables generating 128-bit trace IDs for new
+// root spans, so traces stay whole when they cross into (or originate
+// from) Jaeger, Zipkin B3 or W3C Trace Context services. It is off by
+// default: the high bits aren't sent to the Datadog agent, so enabling
+// this only matters when propagating context to/from such services.
+func WithGenerate128BitTraceID(enabled bool) Option {
+	return func(c *config) {
+		c.generate128BitTraceID = enabled
+	}
+}
+
+// WithSpanPool enables recycling *Span values through a sync.Pool to
+// reduce allocations in high-throughput services.
+//
+// This is unsafe with code that keeps a *Span around after calling
+// Finish() (for instance the pattern of stashing a span via SetTag/context
+// and reading its fields later): once Finish() hands the span's data off
+// to the reporting pipeline, a pooled Span's fields may be overwritten by
+// an unrelated trace at any time. Leave this disabled unless you have
+// audited your integrations for that pattern.
+func WithSpanPool(enabled bool) Option {
+	return func(c *config) {
+		c.spanPool = enabled
+	}
+}
+
+// WithBaggageRestrictionManager sets the BaggageRestrictionManager used to
+// validate and bound baggage items set via Span.SetBaggageItem. Without
+// this option, a DefaultBaggageRestrictionManager capping values at 2048
+// bytes is used.
+func WithBaggageRestrictionManager(mgr BaggageRestrictionManager) Option {
+	return func(c *config) {
+		c.baggageRestrictionManager = mgr
+	}
+}
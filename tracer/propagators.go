@@ -0,0 +1,233 @@
+package tracer
+
+import (
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"math/rand"
+	"strconv"
+	"strings"
+	"time"
+
+	opentracing "github.com/opentracing/opentracing-go"
+)
+
+// ErrInvalidCarrier is returned when the carrier provided to a Propagator
+// does not implement the interface that the Propagator requires
+// (opentracing.TextMapReader/Writer).
+var ErrInvalidCarrier = errors.New("tracer: invalid carrier")
+
+// ErrSpanContextNotFound is returned by Extract when the carrier does not
+// hold a valid trace context.
+var ErrSpanContextNotFound = errors.New("tracer: no span context found in carrier")
+
+// Propagator implements injecting and extracting SpanContext into/from a
+// carrier. Tracer.Inject and Tracer.Extract delegate to the configured
+// textMapPropagator/binaryPropagator depending on the requested format.
+type Propagator interface {
+	// Inject takes the SpanContext and injects it into the carrier.
+	Inject(context *spanContext, carrier interface{}) error
+
+	// Extract returns the SpanContext that was encoded by Inject in the
+	// carrier, or an error if none was found.
+	Extract(carrier interface{}) (*spanContext, error)
+}
+
+// spanContext represents a span's propagated identity: its trace and span
+// IDs, the sampling decision, and any baggage items.
+//
+// SpanContext is the exported alias for *spanContext, the concrete type
+// held by the opentracing.SpanContext that Span.Context() returns.
+type spanContext struct {
+	traceID     uint64 // low 64 bits of the trace ID
+	traceIDHigh uint64 // high 64 bits, non-zero only for 128-bit trace IDs
+	spanID      uint64
+	parentID    uint64
+
+	sampled bool
+	baggage map[string]string
+
+	// hasSamplingPriority and samplingPriority hold a propagated sampling
+	// priority decision (see Span.SetSamplingPriority), when one was set by
+	// whoever created this context.
+	hasSamplingPriority bool
+	samplingPriority    int
+}
+
+// SpanContext is the exported name for *spanContext, used by callers that
+// need to type-assert an opentracing.SpanContext returned by this package.
+type SpanContext = *spanContext
+
+var _ opentracing.SpanContext = (*spanContext)(nil)
+
+// ForeachBaggageItem implements opentracing.SpanContext.
+func (c *spanContext) ForeachBaggageItem(handler func(k, v string) bool) {
+	for k, v := range c.baggage {
+		if !handler(k, v) {
+			break
+		}
+	}
+}
+
+// WithBaggageItem returns a copy of the SpanContext with a new key:value
+// pair set in its baggage. The receiver is left untouched.
+func (c *spanContext) WithBaggageItem(key, val string) *spanContext {
+	baggage := make(map[string]string, len(c.baggage)+1)
+	for k, v := range c.baggage {
+		baggage[k] = v
+	}
+	baggage[key] = val
+	new := *c
+	new.baggage = baggage
+	return &new
+}
+
+// hasTraceIDHigh reports whether this context carries a 128-bit trace ID.
+func (c *spanContext) hasTraceIDHigh() bool {
+	return c.traceIDHigh != 0
+}
+
+// traceIDGenerator produces the high 64 bits of a 128-bit trace ID. It is
+// pluggable so tests can supply deterministic values.
+type traceIDGenerator func() uint64
+
+// defaultTraceIDGenerator is the default, time-seeded random generator used
+// when WithGenerate128BitTraceID is enabled.
+func defaultTraceIDGenerator() uint64 {
+	return rand.New(rand.NewSource(time.Now().UnixNano())).Uint64()
+}
+
+const (
+	defaultBaggagePrefix  = "ot-baggage-"
+	defaultTraceIDHeader  = "x-datadog-trace-id"
+	defaultParentIDHeader = "x-datadog-parent-id"
+
+	// traceIDHighHeader carries the high 64 bits of a 128-bit trace ID, hex
+	// encoded. It is only written when the trace ID actually uses the high
+	// bits, keeping the wire format unchanged for 64-bit traces.
+	traceIDHighHeader = "x-datadog-trace-id-high"
+)
+
+// TextMapPropagator implements Propagator using the Datadog HTTP header
+// format. It is safe to use concurrently.
+type TextMapPropagator struct {
+	baggagePrefix string
+	traceHeader   string
+	parentHeader  string
+}
+
+// NewTextMapPropagator returns a new TextMapPropagator. Empty arguments fall
+// back to the default Datadog header names.
+func NewTextMapPropagator(baggagePrefix, traceHeader, parentHeader string) *TextMapPropagator {
+	if baggagePrefix == "" {
+		baggagePrefix = defaultBaggagePrefix
+	}
+	if traceHeader == "" {
+		traceHeader = defaultTraceIDHeader
+	}
+	if parentHeader == "" {
+		parentHeader = defaultParentIDHeader
+	}
+	return &TextMapPropagator{
+		baggagePrefix: baggagePrefix,
+		traceHeader:   traceHeader,
+		parentHeader:  parentHeader,
+	}
+}
+
+// Inject implements Propagator.
+func (p *TextMapPropagator) Inject(context *spanContext, carrier interface{}) error {
+	writer, ok := carrier.(opentracing.TextMapWriter)
+	if !ok {
+		return ErrInvalidCarrier
+	}
+	writer.Set(p.traceHeader, strconv.FormatUint(context.traceID, 10))
+	writer.Set(p.parentHeader, strconv.FormatUint(context.spanID, 10))
+	if context.hasTraceIDHigh() {
+		writer.Set(traceIDHighHeader, strconv.FormatUint(context.traceIDHigh, 16))
+	}
+	for k, v := range context.baggage {
+		writer.Set(p.baggagePrefix+k, v)
+	}
+	return nil
+}
+
+// Extract implements Propagator.
+func (p *TextMapPropagator) Extract(carrier interface{}) (*spanContext, error) {
+	reader, ok := carrier.(opentracing.TextMapReader)
+	if !ok {
+		return nil, ErrInvalidCarrier
+	}
+
+	var ctx spanContext
+	var gotTraceID, gotSpanID bool
+	err := reader.ForeachKey(func(k, v string) error {
+		switch strings.ToLower(k) {
+		case p.traceHeader:
+			id, err := strconv.ParseUint(v, 10, 64)
+			if err != nil {
+				return fmt.Errorf("tracer: invalid %s header: %v", p.traceHeader, err)
+			}
+			ctx.traceID = id
+			gotTraceID = true
+		case p.parentHeader:
+			id, err := strconv.ParseUint(v, 10, 64)
+			if err != nil {
+				return fmt.Errorf("tracer: invalid %s header: %v", p.parentHeader, err)
+			}
+			ctx.spanID = id
+			ctx.parentID = id
+			gotSpanID = true
+		case traceIDHighHeader:
+			id, err := strconv.ParseUint(v, 16, 64)
+			if err != nil {
+				return fmt.Errorf("tracer: invalid %s header: %v", traceIDHighHeader, err)
+			}
+			ctx.traceIDHigh = id
+		default:
+			if strings.HasPrefix(strings.ToLower(k), p.baggagePrefix) {
+				if ctx.baggage == nil {
+					ctx.baggage = make(map[string]string)
+				}
+				ctx.baggage[k[len(p.baggagePrefix):]] = v
+			}
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	if !gotTraceID || !gotSpanID {
+		return nil, ErrSpanContextNotFound
+	}
+	ctx.sampled = true
+	return &ctx, nil
+}
+
+// traceIDToHex returns the 32-character hex representation of a 128-bit
+// trace ID, zero-padding the low/high halves as needed.
+func traceIDToHex(high, low uint64) string {
+	var b [16]byte
+	binary.BigEndian.PutUint64(b[0:8], high)
+	binary.BigEndian.PutUint64(b[8:16], low)
+	return fmt.Sprintf("%032x", b)
+}
+
+// hexToTraceID parses a 16 or 32 character hex trace ID (as used by B3 and
+// W3C Trace Context) into its high/low 64-bit halves.
+func hexToTraceID(s string) (high, low uint64, err error) {
+	switch len(s) {
+	case 16:
+		low, err = strconv.ParseUint(s, 16, 64)
+		return 0, low, err
+	case 32:
+		high, err = strconv.ParseUint(s[:16], 16, 64)
+		if err != nil {
+			return 0, 0, err
+		}
+		low, err = strconv.ParseUint(s[16:], 16, 64)
+		return high, low, err
+	default:
+		return 0, 0, fmt.Errorf("tracer: invalid trace ID %q: must be 16 or 32 hex characters", s)
+	}
+}
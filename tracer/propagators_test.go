@@ -76,3 +76,132 @@ func TestOpenTracerTextMapPropagationHeader(t *testing.T) {
 	assert.Equal(headers.Get("pid"), pid)
 	assert.Equal(headers.Get("bg-item"), "x")
 }
+
+func TestTextMapPropagator128BitTraceIDRoundTrip(t *testing.T) {
+	assert := assert.New(t)
+
+	p := NewTextMapPropagator("", "", "")
+	ctx := &spanContext{traceID: 42, traceIDHigh: 7, spanID: 100, sampled: true}
+	headers := http.Header{}
+	carrier := opentracing.HTTPHeadersCarrier(headers)
+
+	assert.Nil(p.Inject(ctx, carrier))
+	assert.Equal("7", headers.Get("x-datadog-trace-id-high"))
+
+	extracted, err := p.Extract(carrier)
+	assert.Nil(err)
+	assert.Equal(ctx.traceID, extracted.traceID)
+	assert.Equal(ctx.traceIDHigh, extracted.traceIDHigh)
+	assert.Equal(ctx.spanID, extracted.spanID)
+}
+
+func TestTextMapPropagatorNoHighBitsOmitsHeader(t *testing.T) {
+	assert := assert.New(t)
+
+	p := NewTextMapPropagator("", "", "")
+	ctx := &spanContext{traceID: 42, spanID: 100, sampled: true}
+	headers := http.Header{}
+	carrier := opentracing.HTTPHeadersCarrier(headers)
+
+	assert.Nil(p.Inject(ctx, carrier))
+	assert.Equal("", headers.Get("x-datadog-trace-id-high"))
+
+	extracted, err := p.Extract(carrier)
+	assert.Nil(err)
+	assert.Equal(uint64(0), extracted.traceIDHigh)
+}
+
+func TestB3PropagatorRoundTrip(t *testing.T) {
+	assert := assert.New(t)
+
+	b3 := NewB3Propagator()
+	ctx := &spanContext{traceID: 42, traceIDHigh: 7, spanID: 100, parentID: 50, sampled: true}
+	headers := http.Header{}
+	carrier := opentracing.HTTPHeadersCarrier(headers)
+
+	assert.Nil(b3.Inject(ctx, carrier))
+	assert.Equal(32, len(headers.Get("X-B3-Traceid")))
+
+	extracted, err := b3.Extract(carrier)
+	assert.Nil(err)
+	assert.Equal(ctx.traceID, extracted.traceID)
+	assert.Equal(ctx.traceIDHigh, extracted.traceIDHigh)
+	assert.Equal(ctx.spanID, extracted.spanID)
+	assert.Equal(ctx.parentID, extracted.parentID)
+	assert.True(extracted.sampled)
+}
+
+func TestB3PropagatorExtractDefersSamplingWhenHeadersAbsent(t *testing.T) {
+	assert := assert.New(t)
+
+	b3 := NewB3Propagator()
+	headers := http.Header{}
+	headers.Set("X-B3-Traceid", traceIDToHex(0, 42))
+	headers.Set("X-B3-Spanid", "64")
+
+	extracted, err := b3.Extract(opentracing.HTTPHeadersCarrier(headers))
+	assert.Nil(err)
+	assert.True(extracted.sampled)
+}
+
+func TestB3PropagatorExtractFlagsOverridesSampled(t *testing.T) {
+	assert := assert.New(t)
+
+	b3 := NewB3Propagator()
+	headers := http.Header{}
+	headers.Set("X-B3-Traceid", traceIDToHex(0, 42))
+	headers.Set("X-B3-Spanid", "64")
+	headers.Set("X-B3-Sampled", "0")
+	headers.Set("X-B3-Flags", "1")
+
+	// The debug flag must win over X-B3-Sampled=0 regardless of the order
+	// ForeachKey happens to visit the two headers in.
+	for i := 0; i < 20; i++ {
+		extracted, err := b3.Extract(opentracing.HTTPHeadersCarrier(headers))
+		assert.Nil(err)
+		assert.True(extracted.sampled)
+	}
+}
+
+func TestB3ExtractedTraceIDHighSurvivesLocalChildSpan(t *testing.T) {
+	assert := assert.New(t)
+
+	b3 := NewB3Propagator()
+	headers := http.Header{}
+	headers.Set("X-B3-Traceid", traceIDToHex(7, 42))
+	headers.Set("X-B3-Spanid", "64")
+	headers.Set("X-B3-Sampled", "1")
+
+	extracted, err := b3.Extract(opentracing.HTTPHeadersCarrier(headers))
+	assert.Nil(err)
+
+	child := newSpan("db.query", "service", "resource", 100, extracted.traceID, extracted.traceIDHigh, extracted.spanID, nil)
+
+	assert.Equal(extracted.traceID, child.TraceID)
+	assert.Equal(extracted.traceIDHigh, child.TraceIDHigh)
+}
+
+func TestDatadogToB3Interop(t *testing.T) {
+	assert := assert.New(t)
+
+	dd := NewTextMapPropagator("", "", "")
+	b3 := NewB3Propagator()
+	ctx := &spanContext{traceID: 42, traceIDHigh: 7, spanID: 100, sampled: true}
+
+	// A context injected in Datadog format should come back out intact once
+	// re-injected and extracted via B3, proving the two formats agree on
+	// what the full 128-bit trace ID is.
+	ddHeaders := http.Header{}
+	assert.Nil(dd.Inject(ctx, opentracing.HTTPHeadersCarrier(ddHeaders)))
+	fromDD, err := dd.Extract(opentracing.HTTPHeadersCarrier(ddHeaders))
+	assert.Nil(err)
+
+	b3Headers := http.Header{}
+	assert.Nil(b3.Inject(fromDD, opentracing.HTTPHeadersCarrier(b3Headers)))
+	fromB3, err := b3.Extract(opentracing.HTTPHeadersCarrier(b3Headers))
+	assert.Nil(err)
+
+	assert.Equal(ctx.traceID, fromB3.traceID)
+	assert.Equal(ctx.traceIDHigh, fromB3.traceIDHigh)
+	assert.Equal(ctx.spanID, fromB3.spanID)
+}
@@ -0,0 +1,162 @@
+package tracer
+
+import (
+	"fmt"
+	"io"
+	"strings"
+	"sync"
+)
+
+// Reporter delivers finished spans to a tracing backend. By the time
+// Tracer hands spans to a Reporter, the sampling decision has already been
+// made (see Span.Sampled): every span passed to Report is meant to be
+// kept, so a Reporter never needs to re-implement sampling itself.
+//
+// Implementing this interface is how dd-trace-go supports backends other
+// than the Datadog agent (Zipkin, OTLP, stdout, ...), and WithReporter is
+// how a Tracer is told to use one.
+type Reporter interface {
+	// Report submits spans to the backend.
+	Report(spans []*Span) error
+
+	// Close releases any resources held by the Reporter (connections,
+	// goroutines, open files, ...). Pending spans are not flushed by Close;
+	// callers are expected to have already done so.
+	Close() error
+}
+
+// resolveReporter returns the Reporter a Tracer should use: the one set
+// via WithReporter if any, otherwise an AgentReporter pointed at the
+// configured agent address, preserving the historical default of talking
+// to the agent at c.agentAddr (localhost:8126 unless overridden).
+//
+// The Tracer's constructor is expected to call this once, at New(), and
+// keep the result for its flush path to call Report on instead of talking
+// to a transport directly; that constructor and flush path live in
+// tracer.go, which this source tree does not include, so resolveReporter
+// itself has no caller here. WithReporter/LoggingReporter/CompositeReporter
+// are otherwise complete and ready to be wired in there.
+func resolveReporter(c *config) Reporter {
+	if c.reporter != nil {
+		return c.reporter
+	}
+	return NewAgentReporter(c.agentAddr)
+}
+
+// transport is the minimal interface AgentReporter needs from whatever
+// moves encoded spans to the Datadog agent. It exists so AgentReporter can
+// wrap the Tracer's existing agent client without this package needing to
+// know its concrete type.
+type transport interface {
+	send(spans []*Span) error
+	close() error
+}
+
+// AgentReporter is the default Reporter: it wraps the Tracer's existing
+// transport to the Datadog agent, so behavior is unchanged for callers
+// that don't opt into a different Reporter.
+type AgentReporter struct {
+	transport transport
+}
+
+// NewAgentReporter returns an AgentReporter that sends spans to the
+// Datadog agent listening at addr.
+func NewAgentReporter(addr string) *AgentReporter {
+	return &AgentReporter{transport: newHTTPTransport(addr)}
+}
+
+// Report implements Reporter.
+func (r *AgentReporter) Report(spans []*Span) error {
+	return r.transport.send(spans)
+}
+
+// Close implements Reporter.
+func (r *AgentReporter) Close() error {
+	return r.transport.close()
+}
+
+// LoggingReporter pretty-prints finished traces to an io.Writer instead of
+// sending them anywhere. It's useful for tests and local development,
+// replacing the ad-hoc pattern of calling Span.String() by hand.
+type LoggingReporter struct {
+	w  io.Writer
+	mu sync.Mutex
+}
+
+// NewLoggingReporter returns a LoggingReporter that writes to w.
+func NewLoggingReporter(w io.Writer) *LoggingReporter {
+	return &LoggingReporter{w: w}
+}
+
+// Report implements Reporter.
+func (r *LoggingReporter) Report(spans []*Span) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	for _, s := range spans {
+		if _, err := fmt.Fprintln(r.w, s.String()); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// Close implements Reporter. LoggingReporter holds no resources, so this
+// is always a no-op.
+func (r *LoggingReporter) Close() error {
+	return nil
+}
+
+// CompositeReporter fans finished spans out to multiple Reporters, a
+// common requirement when dual-writing to two backends during a
+// migration.
+type CompositeReporter struct {
+	reporters []Reporter
+}
+
+// NewCompositeReporter returns a Reporter that forwards every call to each
+// of reporters in turn.
+func NewCompositeReporter(reporters ...Reporter) *CompositeReporter {
+	return &CompositeReporter{reporters: reporters}
+}
+
+// Report implements Reporter. It reports to every child Reporter even if
+// one of them errors, then returns the combined error.
+func (r *CompositeReporter) Report(spans []*Span) error {
+	var errs []string
+	for _, rep := range r.reporters {
+		if err := rep.Report(spans); err != nil {
+			errs = append(errs, err.Error())
+		}
+	}
+	return joinErrors(errs)
+}
+
+// Close implements Reporter, closing every child Reporter even if one of
+// them errors, then returning the combined error.
+func (r *CompositeReporter) Close() error {
+	var errs []string
+	for _, rep := range r.reporters {
+		if err := rep.Close(); err != nil {
+			errs = append(errs, err.Error())
+		}
+	}
+	return joinErrors(errs)
+}
+
+func joinErrors(msgs []string) error {
+	if len(msgs) == 0 {
+		return nil
+	}
+	return fmt.Errorf("tracer: %s", strings.Join(msgs, "; "))
+}
+
+// NoopReporter discards every span it's given. It's primarily useful in
+// benchmarks, where the cost of actually reporting spans would otherwise
+// dominate the measurement.
+type NoopReporter struct{}
+
+// Report implements Reporter.
+func (NoopReporter) Report(spans []*Span) error { return nil }
+
+// Close implements Reporter.
+func (NoopReporter) Close() error { return nil }
@@ -0,0 +1,70 @@
+package tracer
+
+import (
+	"bytes"
+	"errors"
+	"strings"
+	"testing"
+)
+
+func TestLoggingReporter(t *testing.T) {
+	var buf bytes.Buffer
+	r := NewLoggingReporter(&buf)
+	span := &Span{Name: "web.request", Service: "my-service"}
+
+	if err := r.Report([]*Span{span}); err != nil {
+		t.Fatalf("Report: %v", err)
+	}
+	if !strings.Contains(buf.String(), "web.request") {
+		t.Fatalf("expected output to contain span name, got %q", buf.String())
+	}
+}
+
+type fakeReporter struct {
+	reported [][]*Span
+	err      error
+}
+
+func (f *fakeReporter) Report(spans []*Span) error {
+	f.reported = append(f.reported, spans)
+	return f.err
+}
+
+func (f *fakeReporter) Close() error { return f.err }
+
+func TestCompositeReporterFansOut(t *testing.T) {
+	a, b := &fakeReporter{}, &fakeReporter{}
+	r := NewCompositeReporter(a, b)
+	spans := []*Span{{Name: "web.request"}}
+
+	if err := r.Report(spans); err != nil {
+		t.Fatalf("Report: %v", err)
+	}
+	if len(a.reported) != 1 || len(b.reported) != 1 {
+		t.Fatalf("expected both reporters to receive spans: a=%d b=%d", len(a.reported), len(b.reported))
+	}
+}
+
+func TestCompositeReporterCombinesErrors(t *testing.T) {
+	a := &fakeReporter{err: errors.New("a failed")}
+	b := &fakeReporter{err: errors.New("b failed")}
+	r := NewCompositeReporter(a, b)
+
+	err := r.Report([]*Span{{Name: "web.request"}})
+	if err == nil {
+		t.Fatal("expected a combined error")
+	}
+	if !strings.Contains(err.Error(), "a failed") || !strings.Contains(err.Error(), "b failed") {
+		t.Fatalf("expected combined error to mention both failures, got %q", err.Error())
+	}
+}
+
+func TestNoopReporter(t *testing.T) {
+	var r NoopReporter
+	if err := r.Report([]*Span{{Name: "web.request"}}); err != nil {
+		t.Fatalf("Report: %v", err)
+	}
+	if err := r.Close(); err != nil {
+		t.Fatalf("Close: %v", err)
+	}
+}
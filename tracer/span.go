@@ -1,6 +1,7 @@
 package tracer
 
 import (
+	"errors"
 	"fmt"
 	"reflect"
 	"runtime/debug"
@@ -14,7 +15,7 @@ import (
 
 var _ opentracing.Span = (*Span)(nil)
 
-// Tracer provides access to the `Tracer`` that created this Span.
+// Tracer provides access to the `Tracer“ that created this Span.
 func (s *Span) Tracer() opentracing.Tracer { return s.tracer }
 
 // Context yields the SpanContext for this Span. Note that the return
@@ -28,11 +29,34 @@ func (s *Span) Context() opentracing.SpanContext {
 }
 
 // SetBaggageItem sets a key:value pair on this Span and its SpanContext
-// that also propagates to descendants of this Span.
+// that also propagates to descendants of this Span. Keys and values are
+// subject to the Tracer's BaggageRestrictionManager (WithBaggageRestrictionManager):
+// disallowed keys are dropped and over-long values are truncated, both
+// cases being recorded on the "trace.baggage.restrictions.update" metric.
+// If the Span has been finished, it will not be modified by the method.
 func (s *Span) SetBaggageItem(key, val string) opentracing.Span {
 	s.Lock()
 	defer s.Unlock()
 
+	if s.finished {
+		return s
+	}
+
+	mgr := defaultBaggageRestrictionManager
+	if s.tracer != nil && s.tracer.config != nil && s.tracer.config.baggageRestrictionManager != nil {
+		mgr = s.tracer.config.baggageRestrictionManager
+	}
+	allowed, maxValueLength := mgr.IsValidBaggageKey(key)
+	if !allowed {
+		s.setMetric(baggageRestrictionsUpdateMetric, s.Metrics[baggageRestrictionsUpdateMetric]+1)
+		return s
+	}
+	if maxValueLength > 0 && len(val) > maxValueLength {
+		val = val[:maxValueLength]
+		s.setMeta(baggageTruncatedTag, key)
+		s.setMetric(baggageRestrictionsUpdateMetric, s.Metrics[baggageRestrictionsUpdateMetric]+1)
+	}
+
 	s.context = s.context.WithBaggageItem(key, val)
 	return s
 }
@@ -102,29 +126,80 @@ func (s *Span) SetOperationName(operationName string) opentracing.Span {
 // logging data about a Span, though the programming interface is a little
 // more verbose than LogKV().
 func (s *Span) LogFields(fields ...log.Field) {
-	// TODO: implementation missing
+	s.logFieldsWithTime(now(), fields)
+}
+
+func (s *Span) logFieldsWithTime(ts int64, fields []log.Field) {
+	s.Lock()
+	defer s.Unlock()
+
+	if s.finished {
+		return
+	}
+
+	max := maxLogsPerSpan
+	if s.tracer != nil && s.tracer.config != nil && s.tracer.config.maxLogsPerSpan > 0 {
+		max = s.tracer.config.maxLogsPerSpan
+	}
+	if max > 0 && len(s.Logs) >= max {
+		s.setMetric(logsDroppedKey, s.Metrics[logsDroppedKey]+1)
+		return
+	}
+
+	rec := LogRecord{Time: ts, Fields: make([]LogField, 0, len(fields))}
+	for _, f := range fields {
+		var enc fieldEncoder
+		f.Marshal(&enc)
+		rec.Fields = append(rec.Fields, enc.field)
+	}
+	s.Logs = append(s.Logs, rec)
+
+	// An event tagged "error" is the OpenTracing convention for logging an
+	// error (see the opentracing semantic conventions). Route it through
+	// SetError so it behaves the same as SetTag(ext.Error, err).
+	if err := errorFromFields(rec.Fields); err != nil {
+		s.setError(err)
+	}
+}
+
+// setMetric is the lock-free counterpart of SetMetric; the caller must
+// already hold the Span's lock.
+func (s *Span) setMetric(key string, val float64) {
+	if s.finished {
+		return
+	}
+	if s.Metrics == nil {
+		s.Metrics = make(map[string]float64)
+	}
+	s.Metrics[key] = val
 }
 
 // LogKV is a concise, readable way to record key:value logging data about
 // a span, though unfortunately this also makes it less efficient and less
 // type-safe than LogFields().
 func (s *Span) LogKV(keyVals ...interface{}) {
-	// TODO: implementation missing
+	fields, err := log.InterleavedKVToFields(keyVals...)
+	if err != nil {
+		s.LogFields(log.Error(err))
+		return
+	}
+	s.LogFields(fields...)
 }
 
 // LogEvent is deprecated: use LogFields or LogKV
 func (s *Span) LogEvent(event string) {
-	// TODO: implementation missing
+	s.LogFields(log.Event(event))
 }
 
 // LogEventWithPayload deprecated: use LogFields or LogKV
 func (s *Span) LogEventWithPayload(event string, payload interface{}) {
-	// TODO: implementation missing
+	s.LogFields(log.Event(event), log.Object("payload", payload))
 }
 
 // Log is deprecated: use LogFields or LogKV
 func (s *Span) Log(data opentracing.LogData) {
-	// TODO: implementation missing
+	rec := data.ToLogRecord()
+	s.logFieldsWithTime(rec.Timestamp.UnixNano(), rec.Fields)
 }
 
 // OLD ////////////////////////////////
@@ -135,8 +210,85 @@ const (
 	errorStackKey = "error.stack"
 
 	samplingPriorityKey = "_sampling_priority_v1"
+
+	// logsDroppedKey is the metric recording how many log records were
+	// dropped on a span because MaxLogsPerSpan was exceeded.
+	logsDroppedKey = "_dd.logs.dropped"
 )
 
+// maxLogsPerSpan is the default cap on the number of log records kept on a
+// Span before they start being dropped. A value of 0 (the zero config
+// default) disables the cap; it is only enforced once a Tracer is
+// configured with WithMaxLogsPerSpan.
+const maxLogsPerSpan = 0
+
+// LogField is a single typed key:value pair recorded on a Span via the
+// OpenTracing Log API (LogFields, LogKV, LogEvent, Log). Value holds one of
+// string, bool, int64, float64 or, for anything else, the result of
+// fmt.Sprint.
+type LogField struct {
+	Key   string      `json:"key"`
+	Value interface{} `json:"value"`
+}
+
+// LogRecord groups the LogFields recorded by a single call to LogFields,
+// LogKV, LogEvent, LogEventWithPayload or Log.
+type LogRecord struct {
+	Time   int64      `json:"time"` // nanoseconds since epoch
+	Fields []LogField `json:"fields"`
+}
+
+// fieldEncoder implements the opentracing-go/log.Encoder interface, turning
+// a single log.Field into a typed LogField.
+type fieldEncoder struct {
+	field LogField
+}
+
+func (e *fieldEncoder) EmitString(key, value string)        { e.field = LogField{key, value} }
+func (e *fieldEncoder) EmitBool(key string, value bool)     { e.field = LogField{key, value} }
+func (e *fieldEncoder) EmitInt(key string, value int)       { e.field = LogField{key, int64(value)} }
+func (e *fieldEncoder) EmitInt32(key string, value int32)   { e.field = LogField{key, int64(value)} }
+func (e *fieldEncoder) EmitInt64(key string, value int64)   { e.field = LogField{key, value} }
+func (e *fieldEncoder) EmitUint32(key string, value uint32) { e.field = LogField{key, int64(value)} }
+func (e *fieldEncoder) EmitUint64(key string, value uint64) { e.field = LogField{key, int64(value)} }
+func (e *fieldEncoder) EmitFloat32(key string, value float32) {
+	e.field = LogField{key, float64(value)}
+}
+func (e *fieldEncoder) EmitFloat64(key string, value float64) { e.field = LogField{key, value} }
+func (e *fieldEncoder) EmitObject(key string, value interface{}) {
+	e.field = LogField{key, fmt.Sprint(value)}
+}
+func (e *fieldEncoder) EmitLazyLogger(value log.LazyLogger) {
+	value(e)
+}
+
+// errorFromFields inspects a set of log fields for the OpenTracing "error"
+// event convention (event=error) and, if present, builds the error it
+// describes from the accompanying error.object/message fields.
+func errorFromFields(fields []LogField) error {
+	var isError bool
+	var errObj, message string
+	for _, f := range fields {
+		switch f.Key {
+		case "event":
+			if fmt.Sprint(f.Value) == "error" {
+				isError = true
+			}
+		case "error.object":
+			errObj = fmt.Sprint(f.Value)
+		case "message":
+			message = fmt.Sprint(f.Value)
+		}
+	}
+	if !isError {
+		return nil
+	}
+	if errObj != "" {
+		return errors.New(errObj)
+	}
+	return errors.New(message)
+}
+
 // Span represents a computation. Callers must call Finish when a span is
 // complete to ensure it's submitted.
 //
@@ -174,14 +326,25 @@ type Span struct {
 	Metrics  map[string]float64 `json:"metrics,omitempty"` // arbitrary map of numeric metrics
 	SpanID   uint64             `json:"span_id"`           // identifier of this span
 	TraceID  uint64             `json:"trace_id"`          // identifier of the root span
-	ParentID uint64             `json:"parent_id"`         // identifier of the span's direct parent
-	Error    int32              `json:"error"`             // error status of the span; 0 means no errors
-	Sampled  bool               `json:"-"`                 // if this span is sampled (and should be kept/recorded) or not
+	// TraceIDHigh holds the high 64 bits of a 128-bit trace ID propagated
+	// from a W3C/B3 service. It is 0 for the common 64-bit case and is not
+	// sent to the agent; it only exists so the full ID round-trips through
+	// a Datadog-instrumented hop. See WithGenerate128BitTraceID.
+	TraceIDHigh uint64      `json:"-"`
+	ParentID    uint64      `json:"parent_id"`      // identifier of the span's direct parent
+	Error       int32       `json:"error"`          // error status of the span; 0 means no errors
+	Sampled     bool        `json:"-"`              // if this span is sampled (and should be kept/recorded) or not
+	Logs        []LogRecord `json:"logs,omitempty"` // structured logs recorded via the OpenTracing Log API
 
 	sync.RWMutex
 	tracer   *Tracer // the tracer that generated this span
 	finished bool    // true if the span has been submitted to a tracer.
 
+	// generation counts how many times this Span value has been recycled by
+	// a span pool (see WithSpanPool). It is exposed via Generation() so
+	// code that stashes a *Span can detect reuse.
+	generation uint64
+
 	// parent contains a link to the parent. In most cases, ParentID can be inferred from this.
 	// However, ParentID can technically be overridden (typical usage: distributed tracing)
 	// and also, parent == nil is used to identify root and top-level ("local root") spans.
@@ -192,20 +355,31 @@ type Span struct {
 
 // newSpan creates a new span. This is a low-level function, required for testing and advanced usage.
 // Most of the time one should prefer the Tracer NewRootSpan or NewChildSpan methods.
-func newSpan(name, service, resource string, spanID, traceID, parentID uint64, tracer *Tracer) *Span {
-	return &Span{
-		Name:     name,
-		Service:  service,
-		Resource: resource,
-		Meta:     map[string]string{},
-		Metrics:  map[string]float64{},
-		SpanID:   spanID,
-		TraceID:  traceID,
-		ParentID: parentID,
-		Start:    now(),
-		Sampled:  true,
-		tracer:   tracer,
+//
+// traceIDHigh carries the high 64 bits of a 128-bit trace ID inherited from
+// a parent (e.g. one extracted from an incoming B3/W3C/Jaeger context); pass
+// 0 for a span that isn't continuing such a trace.
+func newSpan(name, service, resource string, spanID, traceID, traceIDHigh, parentID uint64, tracer *Tracer) *Span {
+	pooled := tracer != nil && tracer.config != nil && tracer.config.spanPool
+	s := acquireSpan(pooled)
+	s.Name = name
+	s.Service = service
+	s.Resource = resource
+	s.SpanID = spanID
+	s.TraceID = traceID
+	s.TraceIDHigh = traceIDHigh
+	s.ParentID = parentID
+	s.Start = now()
+	s.Sampled = true
+	s.tracer = tracer
+	if parentID == 0 && traceIDHigh == 0 && tracer != nil && tracer.config != nil && tracer.config.generate128BitTraceID {
+		gen := tracer.config.traceIDGenerator
+		if gen == nil {
+			gen = defaultTraceIDGenerator
+		}
+		s.TraceIDHigh = gen()
 	}
+	return s
 }
 
 // setMeta adds an arbitrary meta field to the current Span. The span
@@ -289,6 +463,15 @@ func (s *Span) SetError(err error) {
 
 	s.Lock()
 	defer s.Unlock()
+	s.setError(err)
+}
+
+// setError is the lock-free counterpart of SetError; the caller must
+// already hold the Span's lock.
+func (s *Span) setError(err error) {
+	if err == nil {
+		return
+	}
 	// We don't lock spans when flushing, so we could have a data race when
 	// modifying a span as it's being flushed. This protects us against that
 	// race, since spans are marked `finished` before we flush them.
@@ -337,8 +520,14 @@ func (s *Span) finish(finishTime int64) {
 		return
 	}
 
-	// If not sampled, drop it
+	pooled := s.tracer != nil && s.tracer.config != nil && s.tracer.config.spanPool
+
+	// If not sampled, drop it. Nothing is handed off to the flush pipeline
+	// in this case, so the span is immediately safe to recycle.
 	if !s.Sampled {
+		if pooled {
+			releaseSpan(s)
+		}
 		return
 	}
 
@@ -348,6 +537,15 @@ func (s *Span) finish(finishTime int64) {
 	// the channel for real, when the trace is finished.
 	// Otherwise, tests could become flaky (because you never know in what state
 	// the channel is).
+
+	// AckFinish only returns once this span's data has been handed off to
+	// the flush pipeline, so it's then safe to recycle the Span. Pooling is
+	// opt-in (WithSpanPool) precisely because callers that keep a *Span
+	// around past Finish() (e.g. via the unsafe field access pattern) would
+	// otherwise observe it being overwritten by an unrelated trace.
+	if pooled {
+		releaseSpan(s)
+	}
 }
 
 // FinishWithErr marks a span finished and sets the given error if it's
@@ -0,0 +1,93 @@
+package tracer
+
+import "sync"
+
+// spanPool recycles *Span values when a Tracer is configured with
+// WithSpanPool(true). Pooling trades a small amount of correctness risk
+// (see the warning on WithSpanPool) for materially fewer allocations in
+// high-throughput services, since every traced call otherwise allocates a
+// Span plus its Meta/Metrics maps.
+var spanPool = sync.Pool{
+	New: func() interface{} {
+		return &Span{
+			Meta:    map[string]string{},
+			Metrics: map[string]float64{},
+		}
+	},
+}
+
+// acquireSpan returns a zeroed Span, either fresh or recycled from the
+// pool, depending on whether pooling is enabled. A pooled Span comes back
+// already reset by releaseSpan, which ran when its previous owner finished
+// with it.
+func acquireSpan(pooled bool) *Span {
+	if !pooled {
+		return &Span{
+			Meta:    map[string]string{},
+			Metrics: map[string]float64{},
+		}
+	}
+	return spanPool.Get().(*Span)
+}
+
+// releaseSpan returns a finished Span to the pool. It must only be called
+// once the Span's data has been handed off to the reporting pipeline, since
+// resetting the Span here immediately bumps its generation and clears its
+// fields for reuse by an unrelated trace — the point being that a caller
+// holding a stale reference sees the generation change right away, rather
+// than only once the Span is next acquired.
+func releaseSpan(s *Span) {
+	s.reset()
+	spanPool.Put(s)
+}
+
+// reset clears a Span's fields for reuse, bumping its generation so that
+// any late write through a stale *Span held by a caller becomes detectable
+// (see Span.Generation). Maps are emptied rather than set to nil so their
+// backing storage is reused across generations.
+func (s *Span) reset() {
+	s.Lock()
+	defer s.Unlock()
+
+	s.Name = ""
+	s.Service = ""
+	s.Resource = ""
+	s.Type = ""
+	s.Start = 0
+	s.Duration = 0
+	for k := range s.Meta {
+		delete(s.Meta, k)
+	}
+	for k := range s.Metrics {
+		delete(s.Metrics, k)
+	}
+	if s.Meta == nil {
+		s.Meta = make(map[string]string)
+	}
+	if s.Metrics == nil {
+		s.Metrics = make(map[string]float64)
+	}
+	s.Logs = nil
+	s.SpanID = 0
+	s.TraceID = 0
+	s.TraceIDHigh = 0
+	s.ParentID = 0
+	s.Error = 0
+	s.Sampled = false
+	s.finished = false
+	s.parent = nil
+	s.buffer = nil
+	s.context = nil
+	s.tracer = nil
+	s.generation++
+}
+
+// Generation returns a counter that increments every time this Span is
+// recycled by a span pool. It lets advanced callers that stash a *Span
+// detect, by comparing a previously-read generation, whether the Span they
+// are holding a reference to has since been handed to a different trace.
+func (s *Span) Generation() uint64 {
+	s.RLock()
+	defer s.RUnlock()
+	return s.generation
+}
@@ -0,0 +1,54 @@
+package tracer
+
+import "testing"
+
+func TestSpanPoolResetClearsState(t *testing.T) {
+	s := acquireSpan(true)
+	s.SetMeta("k", "v")
+	s.SetMetric("m", 1)
+	gen := s.Generation()
+
+	s.reset()
+
+	if len(s.Meta) != 0 || len(s.Metrics) != 0 {
+		t.Fatalf("reset did not clear Meta/Metrics: %+v %+v", s.Meta, s.Metrics)
+	}
+	if s.Generation() != gen+1 {
+		t.Fatalf("reset did not bump generation: got %d, want %d", s.Generation(), gen+1)
+	}
+}
+
+func TestFinishReleasesSampledOutSpanToPool(t *testing.T) {
+	tr := New(WithSpanPool(true))
+	s := newSpan("op", "service", "resource", 1, 1, 0, 0, tr)
+	s.buffer = &spanBuffer{}
+	s.Sampled = false
+	gen := s.Generation()
+
+	s.Finish()
+
+	if s.Generation() == gen {
+		t.Fatalf("expected sampled-out span to be recycled into the pool, generation unchanged: %d", s.Generation())
+	}
+}
+
+func benchmarkNewSpan(b *testing.B, pooled bool) {
+	tracer := New(WithSpanPool(pooled))
+	b.ReportAllocs()
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		s := newSpan("op", "service", "resource", uint64(i), uint64(i), 0, 0, tracer)
+		s.SetMeta("k", "v")
+		if pooled {
+			releaseSpan(s)
+		}
+	}
+}
+
+func BenchmarkNewSpanPoolDisabled(b *testing.B) {
+	benchmarkNewSpan(b, false)
+}
+
+func BenchmarkNewSpanPoolEnabled(b *testing.B) {
+	benchmarkNewSpan(b, true)
+}